@@ -0,0 +1,87 @@
+// Teleport
+// Copyright (C) 2024 Gravitational, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// Package backend provides a storage abstraction layer over the
+// various databases (etcd, DynamoDB, Firestore, a local directory, an
+// in-memory map, ...) that Teleport can persist its state in.
+package backend
+
+import (
+	"context"
+	"time"
+)
+
+// Item is a key value item
+type Item struct {
+	// Key is an object key
+	Key Key
+	// Value is a value of the key value item
+	Value []byte
+	// Expires is an optional expiry time
+	Expires time.Time
+	// ID is an optional record ID, newer read or write
+	// operations will overwrite the old one otherwise
+	ID int64
+	// LeaseID is an optional lease ID, could be set on objects
+	// with TTL
+	LeaseID int64
+	// Revision is the last recorded revision of the object.
+	Revision string
+}
+
+// Lease represents a lease on an item, as returned by [Backend.Put].
+type Lease struct {
+	// Key is the key of the item
+	Key Key
+	// ID is a lease ID, could be empty
+	ID int64
+	// Revision is the revision of the item at the time the lease was
+	// issued.
+	Revision string
+}
+
+// GetResult provides the result of GetRange request
+type GetResult struct {
+	// Items is a list of items
+	Items []Item
+}
+
+// Backend implements abstract key-value storage database that is used by
+// Teleport services to persist and read state.
+type Backend interface {
+	// Get returns a single item or not found error
+	Get(ctx context.Context, key Key) (*Item, error)
+
+	// GetRange returns query range, limit is set to 0 for unlimited number of
+	// items
+	GetRange(ctx context.Context, startKey Key, endKey Key, limit int) (*GetResult, error)
+
+	// Put puts value into backend (creates if it does not exist, updates it otherwise)
+	Put(ctx context.Context, i Item) (*Lease, error)
+
+	// Delete deletes item by key
+	Delete(ctx context.Context, key Key) error
+
+	// ListPrefix lists the items and common prefixes found directly under
+	// prefix.Prefix, grouping child keys that share a path component past
+	// the delimiter into CommonPrefixes, in the style of the S3
+	// ListObjectsV2 API. Results are paginated via pageToken/pageSize;
+	// nextToken is empty once the listing is exhausted.
+	ListPrefix(ctx context.Context, prefix Prefix, pageToken string, pageSize int) (items []Item, commonPrefixes []Key, nextToken string, err error)
+
+	// Close releases the resources taken up by this backend
+	Close() error
+}
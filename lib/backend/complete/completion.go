@@ -0,0 +1,61 @@
+// Teleport
+// Copyright (C) 2024 Gravitational, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// Package complete maintains a secondary index over backend Keys, keyed by
+// each of their suffixes, so that a partial, suffix- or substring-anchored
+// query (e.g. a user typing the tail of a resource name in the web UI) can
+// be resolved to the full keys that end in or contain it. It is modeled on
+// the completion index used by pkgsite to resolve partial import paths.
+package complete
+
+import "github.com/gravitational/teleport/lib/backend"
+
+// Completion is a single entry in a CompletionIndex: one suffix of a key,
+// along with enough information to resolve it back to the full key and
+// present it to a user.
+type Completion struct {
+	// Suffix is the tail of a key that this completion matches against,
+	// as produced by [backend.Key.Suffixes].
+	Suffix string
+	// FullKey is the key Suffix was derived from.
+	FullKey backend.Key
+	// Kind categorizes the resource the key identifies, e.g. "node",
+	// "role", "token".
+	Kind string
+	// Score ranks this completion relative to others sharing a matching
+	// Suffix prefix; higher scores are returned first.
+	Score int
+}
+
+// CompletionIndex is a secondary index over backend Keys, keyed by each of
+// their suffixes.
+type CompletionIndex interface {
+	// Insert adds every suffix of key to the index, each pointing back at
+	// key with the given kind and score. A prior entry for key, if any,
+	// is not implicitly removed; callers that reinsert an updated key
+	// should Delete it first.
+	Insert(key backend.Key, kind string, score int)
+
+	// Delete removes all entries associated with key from the index.
+	Delete(key backend.Key)
+
+	// Query returns up to limit completions whose Suffix begins with
+	// prefix, sorted by Score descending, then lexically by Suffix. A
+	// prefix that does not start with [backend.Separator] is matched as
+	// though it did, so a bare component such as "cluster-a" matches the
+	// Suffix "/cluster-a". A limit of 0 returns all matches.
+	Query(prefix string, limit int) []Completion
+}
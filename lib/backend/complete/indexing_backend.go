@@ -0,0 +1,68 @@
+// Teleport
+// Copyright (C) 2024 Gravitational, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package complete
+
+import (
+	"context"
+
+	"github.com/gravitational/teleport/lib/backend"
+)
+
+// Classifier derives the completion kind and score under which a key
+// should be indexed, typically from its leading component (e.g.
+// "/nodes/..." -> "node").
+type Classifier func(key backend.Key) (kind string, score int)
+
+// IndexingBackend wraps a [backend.Backend], write-through updating a
+// CompletionIndex on every Put and Delete so that completion queries are
+// always served from the index rather than a scan of the underlying store.
+type IndexingBackend struct {
+	backend.Backend
+	index    CompletionIndex
+	classify Classifier
+}
+
+// NewIndexingBackend returns a Backend that write-throughs Put and Delete
+// calls on b into index, classifying each key via classify.
+func NewIndexingBackend(b backend.Backend, index CompletionIndex, classify Classifier) *IndexingBackend {
+	return &IndexingBackend{Backend: b, index: index, classify: classify}
+}
+
+// Put implements backend.Backend.
+func (b *IndexingBackend) Put(ctx context.Context, i backend.Item) (*backend.Lease, error) {
+	lease, err := b.Backend.Put(ctx, i)
+	if err != nil {
+		return nil, err
+	}
+
+	b.index.Delete(i.Key)
+	kind, score := b.classify(i.Key)
+	b.index.Insert(i.Key, kind, score)
+
+	return lease, nil
+}
+
+// Delete implements backend.Backend.
+func (b *IndexingBackend) Delete(ctx context.Context, key backend.Key) error {
+	if err := b.Backend.Delete(ctx, key); err != nil {
+		return err
+	}
+
+	b.index.Delete(key)
+
+	return nil
+}
@@ -0,0 +1,107 @@
+// Teleport
+// Copyright (C) 2024 Gravitational, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package complete
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gravitational/teleport/lib/backend"
+)
+
+// memoryIndex is a CompletionIndex backed by an in-memory slice of
+// Completions kept sorted by Suffix, so Query can binary search for the
+// start of a matching range. It holds the whole index in memory and does
+// not persist across restarts; callers that need either should shard the
+// index externally or swap in a radix-tree-backed implementation.
+type memoryIndex struct {
+	mu      sync.Mutex
+	entries []Completion
+}
+
+// NewMemoryIndex returns a CompletionIndex backed by a sorted in-memory
+// slice.
+func NewMemoryIndex() CompletionIndex {
+	return &memoryIndex{}
+}
+
+// Insert implements CompletionIndex.
+func (idx *memoryIndex) Insert(key backend.Key, kind string, score int) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	for _, suffix := range key.Suffixes() {
+		i := sort.Search(len(idx.entries), func(i int) bool {
+			return idx.entries[i].Suffix >= suffix
+		})
+		idx.entries = append(idx.entries, Completion{})
+		copy(idx.entries[i+1:], idx.entries[i:])
+		idx.entries[i] = Completion{Suffix: suffix, FullKey: key, Kind: kind, Score: score}
+	}
+}
+
+// Delete implements CompletionIndex.
+func (idx *memoryIndex) Delete(key backend.Key) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	kept := idx.entries[:0]
+	for _, e := range idx.entries {
+		if e.FullKey.Compare(key) != 0 {
+			kept = append(kept, e)
+		}
+	}
+	idx.entries = kept
+}
+
+// Query implements CompletionIndex.
+func (idx *memoryIndex) Query(prefix string, limit int) []Completion {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	// Every stored Suffix carries a leading Separator (key.Suffixes always
+	// starts at a component boundary), but callers naturally pass the bare
+	// component they're typing, e.g. "cluster-a". Normalize so the two
+	// line up instead of requiring every caller to know the internal
+	// representation.
+	if prefix != "" && prefix[0] != backend.Separator {
+		prefix = string(backend.Separator) + prefix
+	}
+
+	start := sort.Search(len(idx.entries), func(i int) bool {
+		return idx.entries[i].Suffix >= prefix
+	})
+
+	var matches []Completion
+	for i := start; i < len(idx.entries) && strings.HasPrefix(idx.entries[i].Suffix, prefix); i++ {
+		matches = append(matches, idx.entries[i])
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		return matches[i].Suffix < matches[j].Suffix
+	})
+
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+
+	return matches
+}
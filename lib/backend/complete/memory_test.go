@@ -0,0 +1,70 @@
+// Teleport
+// Copyright (C) 2024 Gravitational, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package complete
+
+import (
+	"testing"
+
+	"github.com/gravitational/teleport/lib/backend"
+)
+
+func TestMemoryIndexQueryMatchesBareComponent(t *testing.T) {
+	idx := NewMemoryIndex()
+	idx.Insert(backend.NewKey("nodes", "region1", "cluster-a"), "node", 0)
+	idx.Insert(backend.NewKey("kube", "cluster-a"), "kube_cluster", 0)
+	idx.Insert(backend.NewKey("nodes", "region1", "cluster-b"), "node", 0)
+
+	// A user typing the bare component, with no leading separator, is the
+	// motivating use case: both resources ending in "cluster-a" should be
+	// found even though the stored index key carries a leading separator.
+	matches := idx.Query("cluster-a", 0)
+	if len(matches) != 2 {
+		t.Fatalf("Query(%q) returned %d matches, want 2: %+v", "cluster-a", len(matches), matches)
+	}
+
+	gotKeys := map[string]bool{}
+	for _, m := range matches {
+		gotKeys[m.FullKey.String()] = true
+	}
+	for _, want := range []string{"/nodes/region1/cluster-a", "/kube/cluster-a"} {
+		if !gotKeys[want] {
+			t.Errorf("Query(%q) missing expected match %q, got %+v", "cluster-a", want, matches)
+		}
+	}
+}
+
+func TestMemoryIndexQueryScoreOrdering(t *testing.T) {
+	idx := NewMemoryIndex()
+	idx.Insert(backend.NewKey("nodes", "cluster-a"), "node", 1)
+	idx.Insert(backend.NewKey("kube", "cluster-a"), "kube_cluster", 5)
+
+	matches := idx.Query("cluster-a", 0)
+	if len(matches) != 2 || matches[0].FullKey.String() != "/kube/cluster-a" {
+		t.Fatalf("Query() = %+v, want /kube/cluster-a first (higher score)", matches)
+	}
+}
+
+func TestMemoryIndexDelete(t *testing.T) {
+	idx := NewMemoryIndex()
+	key := backend.NewKey("nodes", "cluster-a")
+	idx.Insert(key, "node", 0)
+	idx.Delete(key)
+
+	if matches := idx.Query("cluster-a", 0); len(matches) != 0 {
+		t.Fatalf("Query() after Delete() = %+v, want no matches", matches)
+	}
+}
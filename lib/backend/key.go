@@ -34,6 +34,120 @@ type Key struct {
 // Separator is used as a separator between key parts
 const Separator = '/'
 
+// noEnd is the sentinel string form of a Key with no end, i.e. one for
+// which [RangeEnd] could not produce a successor because every byte of
+// its string form was already 0xff. 0xff can't occur in a valid UTF-8
+// key, so it sorts after every real key.
+const noEnd = "\xff"
+
+// KeyDecoder controls how KeyFromString-style parsing decodes the
+// escaping applied by EncodeComponent. The zero value decodes normally.
+// Holding one per backend instance (rather than a package-level setting)
+// lets two backends in the same process - e.g. two clusters, one migrated
+// and one not - independently choose legacy vs. migrated decoding without
+// racing each other.
+type KeyDecoder struct {
+	// Legacy, when true, skips component decoding so keys stored before
+	// the escaping codec was introduced are read back byte-for-byte,
+	// instead of having an incidental "%2F" or "%25" in a component
+	// misinterpreted as escaping.
+	Legacy bool
+}
+
+// KeyFromString parses s into a Key, honoring d's Legacy setting. In
+// Legacy mode this is a byte-for-byte round trip: s is kept verbatim as
+// the Key's string form, and components are taken as split, without
+// being decoded. Building the Key via NewKey would re-run EncodeComponent
+// on them, re-escaping any literal '%' a legacy component happens to
+// contain and corrupting the very data Legacy mode exists to preserve.
+func (d KeyDecoder) KeyFromString(s string) Key {
+	components := strings.Split(s, string(Separator))
+	if components[0] == "" && len(components) > 1 {
+		components = components[1:]
+	}
+
+	if d.Legacy {
+		return Key{
+			components: components,
+			s:          s,
+			exactKey:   s == string(Separator) || (len(s) > 0 && s[len(s)-1] == Separator),
+		}
+	}
+
+	for i, c := range components {
+		if decoded, err := DecodeComponent(c); err == nil {
+			components[i] = decoded
+		}
+	}
+
+	return NewKey(components...)
+}
+
+// EncodeComponent escapes a single path component so that it can be
+// embedded in a key's string form without being mistaken for a component
+// boundary. '%' is always percent-encoded, not just when Separator is
+// present: DecodeComponent has no way to tell "this was escaped" from "this
+// literal byte sequence happens to look like an escape", so every literal
+// '%' has to be escaped unconditionally for the round trip to be safe,
+// even in components that don't contain Separator. It is a no-op for the
+// overwhelming majority of components, which contain neither '%' nor
+// Separator and so serialize exactly as they did before this encoding was
+// introduced.
+func EncodeComponent(s string) string {
+	if !strings.ContainsAny(s, "%"+string(Separator)) {
+		return s
+	}
+
+	var b strings.Builder
+	b.Grow(len(s))
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '%':
+			b.WriteString("%25")
+		case Separator:
+			b.WriteString("%2F")
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+
+	return b.String()
+}
+
+// DecodeComponent reverses EncodeComponent, unescaping any "%25" and
+// "%2F"/"%2f" sequences back to '%' and [Separator]. It returns an error
+// if s contains a malformed percent-escape.
+func DecodeComponent(s string) (string, error) {
+	if !strings.ContainsRune(s, '%') {
+		return s, nil
+	}
+
+	var b strings.Builder
+	b.Grow(len(s))
+	for i := 0; i < len(s); i++ {
+		if s[i] != '%' {
+			b.WriteByte(s[i])
+			continue
+		}
+
+		if i+2 >= len(s) {
+			return "", fmt.Errorf("invalid percent-encoding in key component %q", s)
+		}
+
+		switch strings.ToUpper(s[i+1 : i+3]) {
+		case "25":
+			b.WriteByte('%')
+		case "2F":
+			b.WriteByte(Separator)
+		default:
+			return "", fmt.Errorf("invalid percent-encoding in key component %q", s)
+		}
+		i += 2
+	}
+
+	return b.String(), nil
+}
+
 // NewKey joins parts into path separated by Separator,
 // makes sure path always starts with Separator ("/")
 func NewKey(components ...string) Key {
@@ -52,12 +166,12 @@ func ExactKey(components ...string) Key {
 	return k
 }
 
+// KeyFromString parses s into a Key using the default (non-legacy)
+// decoding. Backends that need per-instance control over legacy decoding,
+// e.g. during a migration to the escaped encoding, should use a
+// [KeyDecoder] instead.
 func KeyFromString(s string) Key {
-	components := strings.Split(s, string(Separator))
-	if components[0] == "" && len(components) > 1 {
-		components = components[1:]
-	}
-	return NewKey(components...)
+	return KeyDecoder{}.KeyFromString(s)
 }
 
 func (k Key) IsZero() bool {
@@ -65,7 +179,13 @@ func (k Key) IsZero() bool {
 }
 
 func internalKey(internalPrefix string, components ...string) Key {
-	return Key{components: components, s: strings.Join(append([]string{internalPrefix}, components...), string(Separator))}
+	encoded := make([]string, 0, len(components)+1)
+	encoded = append(encoded, internalPrefix)
+	for _, c := range components {
+		encoded = append(encoded, EncodeComponent(c))
+	}
+
+	return Key{components: components, s: strings.Join(encoded, string(Separator))}
 }
 
 func (k Key) ExactKey() Key {
@@ -80,20 +200,49 @@ func (k Key) ExactKey() Key {
 // each component concatenated together via the [Separator].
 func (k Key) String() string {
 	if k.noEnd {
-		return string(noEnd)
+		return noEnd
 	}
 
 	return k.s
 }
 
 // HasPrefix reports whether the key begins with prefix.
+//
+// Deprecated: this performs a raw string prefix match, so it considers
+// "/nodes/cluster-a" to have prefix "/nodes/cluster", even though
+// "cluster" is not a path component of the key. Use [Key.HasComponentPrefix]
+// instead, which respects the [Separator] component boundary.
 func (k Key) HasPrefix(prefix Key) bool {
 	return strings.HasPrefix(k.s, prefix.s)
 }
 
+// HasComponentPrefix reports whether the key begins with prefix at a
+// component boundary. Unlike [Key.HasPrefix], it does not match a key
+// against a prefix that only shares a partial path component, e.g.
+// "/nodes/cluster-a" does not have the component prefix "/nodes/cluster".
+//
+// This mirrors the algorithm used by cmd/go/internal/str.HasPathPrefix:
+// prefix matches iff the lengths are equal, the prefix is empty, the
+// prefix already ends in [Separator], or the byte in the key immediately
+// following the prefix is [Separator].
+func (k Key) HasComponentPrefix(prefix Key) bool {
+	if !strings.HasPrefix(k.s, prefix.s) {
+		return false
+	}
+
+	return len(k.s) == len(prefix.s) ||
+		prefix.s == "" ||
+		prefix.s[len(prefix.s)-1] == Separator ||
+		k.s[len(prefix.s)] == Separator
+}
+
 // TrimPrefix returns the key without the provided leading prefix string.
 // If the key doesn't start with prefix, it is returned unchanged.
 func (k Key) TrimPrefix(prefix Key) Key {
+	if !k.HasComponentPrefix(prefix) {
+		return k
+	}
+
 	key := strings.TrimPrefix(k.s, prefix.s)
 	if key == "" {
 		return Key{}
@@ -111,13 +260,40 @@ func (k Key) AppendKey(p Key) Key {
 }
 
 // HasSuffix reports whether the key ends with suffix.
+//
+// Deprecated: this performs a raw string suffix match, so it considers
+// "/nodes/cluster-a" to have suffix "cluster-a", but also "/nodes/cluster-a"
+// to have suffix "ster-a", which straddles a path component. Use
+// [Key.HasComponentSuffix] instead, which respects the [Separator]
+// component boundary.
 func (k Key) HasSuffix(suffix Key) bool {
 	return strings.HasSuffix(k.s, suffix.s)
 }
 
+// HasComponentSuffix reports whether the key ends with suffix at a
+// component boundary. The same rule as [Key.HasComponentPrefix] is
+// applied against the leading [Separator] of the remainder: suffix
+// matches iff the lengths are equal, the suffix is empty, the suffix
+// already starts with [Separator], or the byte in the key immediately
+// preceding the suffix is [Separator].
+func (k Key) HasComponentSuffix(suffix Key) bool {
+	if !strings.HasSuffix(k.s, suffix.s) {
+		return false
+	}
+
+	return len(k.s) == len(suffix.s) ||
+		suffix.s == "" ||
+		suffix.s[0] == Separator ||
+		k.s[len(k.s)-len(suffix.s)-1] == Separator
+}
+
 // TrimSuffix returns the key without the provided trailing suffix string.
 // If the key doesn't end with suffix, it is returned unchanged.
 func (k Key) TrimSuffix(suffix Key) Key {
+	if !k.HasComponentSuffix(suffix) {
+		return k
+	}
+
 	key := strings.TrimSuffix(k.s, suffix.s)
 	if key == "" {
 		return Key{}
@@ -130,29 +306,82 @@ func (k Key) Components() []string {
 	return slices.Clone(k.components)
 }
 
+// Suffixes returns every successive tail of the key, starting at a
+// component boundary, from the longest (the full key) to the shortest
+// (its last component). For example, the key "/a/b/c" yields
+// ["/a/b/c", "/b/c", "/c"]. It is used to build a secondary index for
+// suffix/substring completion over keys.
+func (k Key) Suffixes() []string {
+	suffixes := make([]string, 0, len(k.components))
+	for i := range k.components {
+		suffixes = append(suffixes, internalKey("", k.components[i:]...).s)
+	}
+
+	return suffixes
+}
+
 func (k Key) Compare(o Key) int {
 	return strings.Compare(k.s, o.s)
 }
 
+// RangeEnd returns the end of the range that covers all keys having k as a
+// component prefix, for use as the endKey argument to [Backend.GetRange].
+// It works by incrementing the last byte of k's string form, e.g. the range
+// end of "/a" is "/b", which sorts after every key beginning with "/a",
+// such as "/a/z".
+func RangeEnd(k Key) Key {
+	bs := []byte(k.s)
+	for i := len(bs) - 1; i >= 0; i-- {
+		if bs[i] < 0xff {
+			bs[i]++
+			bs = bs[:i+1]
+			return Key{s: string(bs)}
+		}
+	}
+
+	// all 0xff bytes (or empty key), there is no end to the range
+	return Key{noEnd: true}
+}
+
 // Scan implement sql.Scanner, allowing a [Key] to
 // be directly retrieved from sql backends without
 // an intermediary object.
 func (k *Key) Scan(scan any) error {
+	return KeyDecoder{}.Scan(k, scan)
+}
+
+// Scan is the sql.Scanner implementation behind [Key.Scan], honoring d's
+// Legacy setting. Backends that read a column written before the
+// escaping codec was introduced can't plumb a per-instance KeyDecoder
+// into database/sql directly, since Scan(any) error is a fixed interface
+// — they should instead scan into a [LegacyKey], which calls this with
+// Legacy set.
+func (d KeyDecoder) Scan(k *Key, scan any) error {
 	switch key := scan.(type) {
 	case []byte:
 		if len(key) == 0 {
 			return nil
 		}
-		*k = KeyFromString(string(bytes.Clone(key)))
+		*k = d.KeyFromString(string(bytes.Clone(key)))
 	case string:
 		if len(key) == 0 {
 			return nil
 		}
 
-		*k = KeyFromString(strings.Clone(key))
+		*k = d.KeyFromString(strings.Clone(key))
 	default:
 		return fmt.Errorf("invalid Key type %T", scan)
 	}
 
 	return nil
 }
+
+// LegacyKey is a [Key] that scans through a Legacy [KeyDecoder], for
+// backends still reading columns written before the escaping codec was
+// introduced. Convert with Key(legacyKey) once scanned.
+type LegacyKey Key
+
+// Scan implements sql.Scanner.
+func (k *LegacyKey) Scan(scan any) error {
+	return KeyDecoder{Legacy: true}.Scan((*Key)(k), scan)
+}
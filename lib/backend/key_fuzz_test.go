@@ -0,0 +1,78 @@
+// Teleport
+// Copyright (C) 2024 Gravitational, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package backend
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestKeyComponentRoundTrip(t *testing.T) {
+	// The motivating case: a component that legitimately contains a
+	// Separator, such as a resource name with a slash in it.
+	k := NewKey("a/b", "c")
+	want := []string{"a/b", "c"}
+	if got := k.Components(); !slices.Equal(got, want) {
+		t.Fatalf("Components() = %v, want %v", got, want)
+	}
+
+	roundTripped := KeyFromString(k.String())
+	if got := roundTripped.Components(); !slices.Equal(got, want) {
+		t.Fatalf("KeyFromString(k.String()).Components() = %v, want %v", got, want)
+	}
+
+	// A component whose value merely looks like an escape sequence is
+	// escaped on write, so decoding on read must not corrupt it.
+	literal := NewKey("report%2Fq1")
+	if got := KeyFromString(literal.String()).Components(); !slices.Equal(got, []string{"report%2Fq1"}) {
+		t.Fatalf("KeyFromString(k.String()).Components() = %v, want %v", got, []string{"report%2Fq1"})
+	}
+}
+
+func TestKeyDecoderLegacyRoundTrip(t *testing.T) {
+	const raw = "/foo%2Fbar/baz"
+
+	got := KeyDecoder{Legacy: true}.KeyFromString(raw).String()
+	if got != raw {
+		t.Fatalf("KeyDecoder{Legacy: true}.KeyFromString(%q).String() = %q, want unchanged %q", raw, got, raw)
+	}
+
+	var legacy LegacyKey
+	if err := (&legacy).Scan(raw); err != nil {
+		t.Fatalf("LegacyKey.Scan() = %v", err)
+	}
+	if got := Key(legacy).String(); got != raw {
+		t.Fatalf("LegacyKey.Scan(%q) then String() = %q, want unchanged %q", raw, got, raw)
+	}
+}
+
+func FuzzKeyFromStringRoundTrip(f *testing.F) {
+	f.Add("a", "b")
+	f.Add("a/b", "c")
+	f.Add("report%2Fq1", "x")
+	f.Add("100%", "done")
+	f.Add("", "")
+	f.Add("%", "/")
+
+	f.Fuzz(func(t *testing.T, a, b string) {
+		k := NewKey(a, b)
+		got := KeyFromString(k.String())
+		if !slices.Equal(got.Components(), k.Components()) {
+			t.Fatalf("KeyFromString(k.String()) = %v, want %v (k.String() = %q)", got.Components(), k.Components(), k.String())
+		}
+	})
+}
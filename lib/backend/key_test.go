@@ -0,0 +1,147 @@
+// Teleport
+// Copyright (C) 2024 Gravitational, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package backend
+
+import "testing"
+
+func TestKeyHasComponentPrefix(t *testing.T) {
+	tests := []struct {
+		name   string
+		key    Key
+		prefix Key
+		want   bool
+	}{
+		{
+			name:   "adjacent sibling is not a prefix",
+			key:    NewKey("foo", "barbaz"),
+			prefix: NewKey("foo", "bar"),
+			want:   false,
+		},
+		{
+			name:   "component boundary matches",
+			key:    NewKey("foo", "bar"),
+			prefix: NewKey("foo"),
+			want:   true,
+		},
+		{
+			name:   "exact key is its own prefix",
+			key:    NewKey("foo", "bar"),
+			prefix: NewKey("foo", "bar"),
+			want:   true,
+		},
+		{
+			name:   "empty prefix always matches",
+			key:    NewKey("foo", "bar"),
+			prefix: Key{},
+			want:   true,
+		},
+		{
+			name:   "prefix ending in separator matches at boundary",
+			key:    NewKey("foo", "bar"),
+			prefix: ExactKey("foo"),
+			want:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.key.HasComponentPrefix(tt.prefix); got != tt.want {
+				t.Errorf("HasComponentPrefix() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeyTrimPrefix(t *testing.T) {
+	key := NewKey("foo", "barbaz")
+	unchanged := key.TrimPrefix(NewKey("foo", "bar"))
+	if unchanged.String() != key.String() {
+		t.Errorf("TrimPrefix() on a non-matching partial component = %q, want unchanged %q", unchanged.String(), key.String())
+	}
+
+	trimmed := NewKey("foo", "bar").TrimPrefix(NewKey("foo"))
+	if want := NewKey("bar").String(); trimmed.String() != want {
+		t.Errorf("TrimPrefix() = %q, want %q", trimmed.String(), want)
+	}
+
+	exact := NewKey("foo", "bar").TrimPrefix(NewKey("foo", "bar"))
+	if !exact.IsZero() {
+		t.Errorf("TrimPrefix() of the exact key = %q, want zero key", exact.String())
+	}
+}
+
+func TestKeyHasComponentSuffix(t *testing.T) {
+	tests := []struct {
+		name   string
+		key    Key
+		suffix Key
+		want   bool
+	}{
+		{
+			name:   "suffix straddling a component is rejected",
+			key:    NewKey("foo", "barbaz"),
+			suffix: NewKey("baz"),
+			want:   false,
+		},
+		{
+			name:   "component boundary matches",
+			key:    NewKey("foo", "bar"),
+			suffix: NewKey("bar"),
+			want:   true,
+		},
+		{
+			name:   "exact key is its own suffix",
+			key:    NewKey("foo", "bar"),
+			suffix: NewKey("foo", "bar"),
+			want:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.key.HasComponentSuffix(tt.suffix); got != tt.want {
+				t.Errorf("HasComponentSuffix() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeyTrimSuffix(t *testing.T) {
+	key := NewKey("foo", "barbaz")
+	unchanged := key.TrimSuffix(NewKey("baz"))
+	if unchanged.String() != key.String() {
+		t.Errorf("TrimSuffix() on a straddling partial component = %q, want unchanged %q", unchanged.String(), key.String())
+	}
+
+	trimmed := NewKey("foo", "bar").TrimSuffix(NewKey("bar"))
+	if want := NewKey("foo").String(); trimmed.String() != want {
+		t.Errorf("TrimSuffix() = %q, want %q", trimmed.String(), want)
+	}
+}
+
+func TestKeySuffixes(t *testing.T) {
+	got := NewKey("a", "b", "c").Suffixes()
+	want := []string{"/a/b/c", "/b/c", "/c"}
+	if len(got) != len(want) {
+		t.Fatalf("Suffixes() = %v, want %v", got, want)
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Fatalf("Suffixes() = %v, want %v", got, want)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+// Teleport
+// Copyright (C) 2024 Gravitational, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package backend
+
+import (
+	"context"
+	"strings"
+)
+
+// Prefix describes a hierarchical listing query over backend Keys, modeled
+// on the prefix+delimiter protocol of the S3 ListObjectsV2 API. A listing
+// groups keys that share everything up to and including the next Delimiter
+// into a single "common prefix" (akin to a directory), and returns the
+// remaining keys as leaf Items.
+type Prefix struct {
+	// Prefix restricts the listing to keys beginning with this string.
+	Prefix string
+	// Delimiter groups keys sharing a component after Prefix into a
+	// common prefix instead of returning them as individual items.
+	Delimiter string
+	// HasPrefix is true when Prefix should be applied. It disambiguates
+	// an intentional empty Prefix from one that was never set.
+	HasPrefix bool
+	// HasDelimiter is true when Delimiter should be applied. It
+	// disambiguates an intentional empty Delimiter (flat listing) from
+	// one that was never set.
+	HasDelimiter bool
+}
+
+// FilePrefix splits Prefix on the last occurrence of Delimiter, returning
+// the leading directory portion (including the trailing delimiter) and the
+// remaining partial component. If Delimiter is unset or does not occur in
+// Prefix, dir is empty and file is the whole Prefix.
+func (p Prefix) FilePrefix() (dir, file string) {
+	if !p.HasDelimiter || p.Delimiter == "" {
+		return "", p.Prefix
+	}
+
+	idx := strings.LastIndex(p.Prefix, p.Delimiter)
+	if idx < 0 {
+		return "", p.Prefix
+	}
+
+	return p.Prefix[:idx+len(p.Delimiter)], p.Prefix[idx+len(p.Delimiter):]
+}
+
+// ListPrefixFromGetRange implements [Backend.ListPrefix] generically in
+// terms of GetRange, for backends that have no native delimiter-aware
+// listing call. It scans keys lexically from Prefix.Prefix (or pageToken,
+// if resuming), and for each key checks whether it has a Delimiter
+// occurring after the prefix: if so, the directory portion up to and
+// including that Delimiter is folded into commonPrefixes (each reported at
+// most once per page); otherwise the key is returned as a leaf Item.
+func ListPrefixFromGetRange(ctx context.Context, b Backend, prefix Prefix, pageToken string, pageSize int) (items []Item, commonPrefixes []Key, nextToken string, err error) {
+	if pageSize <= 0 {
+		pageSize = 100
+	}
+
+	keyPrefix := ""
+	if prefix.HasPrefix {
+		keyPrefix = prefix.Prefix
+	}
+
+	startKey := KeyFromString(keyPrefix)
+	if pageToken != "" {
+		startKey = KeyFromString(pageToken)
+	}
+	endKey := RangeEnd(KeyFromString(keyPrefix))
+
+	seenPrefixes := make(map[string]struct{})
+	for len(items)+len(commonPrefixes) < pageSize {
+		result, err := b.GetRange(ctx, startKey, endKey, pageSize)
+		if err != nil {
+			return nil, nil, "", err
+		}
+		if len(result.Items) == 0 {
+			return items, commonPrefixes, "", nil
+		}
+
+		for _, item := range result.Items {
+			rest := strings.TrimPrefix(item.Key.s, keyPrefix)
+
+			if prefix.HasDelimiter && prefix.Delimiter != "" {
+				if idx := strings.Index(rest, prefix.Delimiter); idx >= 0 {
+					dir := keyPrefix + rest[:idx+len(prefix.Delimiter)]
+					if _, ok := seenPrefixes[dir]; !ok {
+						seenPrefixes[dir] = struct{}{}
+						commonPrefixes = append(commonPrefixes, KeyFromString(dir))
+					}
+					startKey = RangeEnd(KeyFromString(dir))
+					if len(items)+len(commonPrefixes) >= pageSize {
+						return items, commonPrefixes, startKey.s, nil
+					}
+					continue
+				}
+			}
+
+			items = append(items, item)
+			// Resume just past this key, not at RangeEnd, so that keys
+			// extending it (e.g. "/a/x" after "/a") are not skipped.
+			startKey = Key{s: item.Key.s + "\x00"}
+			if len(items)+len(commonPrefixes) >= pageSize {
+				return items, commonPrefixes, startKey.s, nil
+			}
+		}
+	}
+
+	return items, commonPrefixes, startKey.s, nil
+}
@@ -0,0 +1,232 @@
+// Teleport
+// Copyright (C) 2024 Gravitational, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package backend
+
+import (
+	"context"
+	"sort"
+	"testing"
+)
+
+func TestPrefixFilePrefix(t *testing.T) {
+	tests := []struct {
+		name     string
+		prefix   Prefix
+		wantDir  string
+		wantFile string
+	}{
+		{
+			name:     "dir/file split on last delimiter",
+			prefix:   Prefix{Prefix: "/nodes/clus", Delimiter: "/", HasDelimiter: true},
+			wantDir:  "/nodes/",
+			wantFile: "clus",
+		},
+		{
+			name:     "delimiter does not occur in prefix",
+			prefix:   Prefix{Prefix: "nodes", Delimiter: "/", HasDelimiter: true},
+			wantDir:  "",
+			wantFile: "nodes",
+		},
+		{
+			name:     "delimiter unset",
+			prefix:   Prefix{Prefix: "/nodes/cluster-a"},
+			wantDir:  "",
+			wantFile: "/nodes/cluster-a",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir, file := tt.prefix.FilePrefix()
+			if dir != tt.wantDir || file != tt.wantFile {
+				t.Errorf("FilePrefix() = (%q, %q), want (%q, %q)", dir, file, tt.wantDir, tt.wantFile)
+			}
+		})
+	}
+}
+
+// memoryBackend is a minimal, sorted-slice backed Backend used only to
+// exercise ListPrefixFromGetRange; it is not a general-purpose backend
+// implementation.
+type memoryBackend struct {
+	items []Item
+}
+
+func newMemoryBackend(keys ...string) *memoryBackend {
+	b := &memoryBackend{}
+	for _, k := range keys {
+		b.items = append(b.items, Item{Key: KeyFromString(k)})
+	}
+	sort.Slice(b.items, func(i, j int) bool {
+		return b.items[i].Key.Compare(b.items[j].Key) < 0
+	})
+
+	return b
+}
+
+func (b *memoryBackend) Get(ctx context.Context, key Key) (*Item, error) {
+	for _, item := range b.items {
+		if item.Key.Compare(key) == 0 {
+			return &item, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (b *memoryBackend) GetRange(ctx context.Context, startKey Key, endKey Key, limit int) (*GetResult, error) {
+	var result GetResult
+	for _, item := range b.items {
+		if item.Key.Compare(startKey) < 0 {
+			continue
+		}
+		if !endKey.noEnd && item.Key.Compare(endKey) >= 0 {
+			break
+		}
+		result.Items = append(result.Items, item)
+		if limit > 0 && len(result.Items) >= limit {
+			break
+		}
+	}
+
+	return &result, nil
+}
+
+func (b *memoryBackend) Put(ctx context.Context, i Item) (*Lease, error) {
+	b.items = append(b.items, i)
+	sort.Slice(b.items, func(x, y int) bool {
+		return b.items[x].Key.Compare(b.items[y].Key) < 0
+	})
+
+	return &Lease{Key: i.Key}, nil
+}
+
+func (b *memoryBackend) Delete(ctx context.Context, key Key) error {
+	for i, item := range b.items {
+		if item.Key.Compare(key) == 0 {
+			b.items = append(b.items[:i], b.items[i+1:]...)
+			return nil
+		}
+	}
+
+	return nil
+}
+
+func (b *memoryBackend) ListPrefix(ctx context.Context, prefix Prefix, pageToken string, pageSize int) ([]Item, []Key, string, error) {
+	return ListPrefixFromGetRange(ctx, b, prefix, pageToken, pageSize)
+}
+
+func (b *memoryBackend) Close() error { return nil }
+
+func keyStrings(keys []Key) []string {
+	out := make([]string, len(keys))
+	for i, k := range keys {
+		out[i] = k.String()
+	}
+
+	return out
+}
+
+func itemKeyStrings(items []Item) []string {
+	keys := make([]Key, len(items))
+	for i, item := range items {
+		keys[i] = item.Key
+	}
+
+	return keyStrings(keys)
+}
+
+func equalStrings(t *testing.T, got, want []string) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	}
+}
+
+func TestListPrefixFromGetRangeEmptyDelimiter(t *testing.T) {
+	b := newMemoryBackend("/nodes/cluster-a", "/nodes/cluster-b", "/roles/admin")
+
+	items, commonPrefixes, nextToken, err := b.ListPrefix(context.Background(), Prefix{
+		Prefix: "/nodes/", HasPrefix: true,
+		Delimiter: "", HasDelimiter: true,
+	}, "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if nextToken != "" {
+		t.Fatalf("expected listing to be exhausted, got nextToken %q", nextToken)
+	}
+	equalStrings(t, keyStrings(commonPrefixes), nil)
+	equalStrings(t, itemKeyStrings(items), []string{"/nodes/cluster-a", "/nodes/cluster-b"})
+}
+
+func TestListPrefixFromGetRangeMultiLevel(t *testing.T) {
+	b := newMemoryBackend(
+		"/nodes/region1/cluster-a",
+		"/nodes/region1/cluster-b",
+		"/nodes/region2/cluster-c",
+		"/nodes/standalone",
+		"/roles/admin",
+	)
+
+	items, commonPrefixes, _, err := b.ListPrefix(context.Background(), Prefix{
+		Prefix: "/nodes/", HasPrefix: true,
+		Delimiter: "/", HasDelimiter: true,
+	}, "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	equalStrings(t, itemKeyStrings(items), []string{"/nodes/standalone"})
+	equalStrings(t, keyStrings(commonPrefixes), []string{"/nodes/region1/", "/nodes/region2/"})
+}
+
+func TestListPrefixFromGetRangePagination(t *testing.T) {
+	b := newMemoryBackend(
+		"/nodes/region1/cluster-a",
+		"/nodes/region2/cluster-b",
+		"/nodes/region3/cluster-c",
+	)
+
+	p := Prefix{Prefix: "/nodes/", HasPrefix: true, Delimiter: "/", HasDelimiter: true}
+
+	firstPage, firstCommon, nextToken, err := b.ListPrefix(context.Background(), p, "", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(firstPage) != 0 || len(firstCommon) != 1 || nextToken == "" {
+		t.Fatalf("unexpected first page: items=%v commonPrefixes=%v nextToken=%q", firstPage, firstCommon, nextToken)
+	}
+
+	secondPage, secondCommon, nextToken2, err := b.ListPrefix(context.Background(), p, nextToken, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(secondPage) != 0 || len(secondCommon) != 1 || nextToken2 == "" {
+		t.Fatalf("unexpected second page: items=%v commonPrefixes=%v nextToken=%q", secondPage, secondCommon, nextToken2)
+	}
+
+	if firstCommon[0].Compare(secondCommon[0]) == 0 {
+		t.Fatalf("expected distinct common prefixes across pages, got %v twice", firstCommon[0])
+	}
+}